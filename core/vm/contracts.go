@@ -0,0 +1,108 @@
+// Package vm implements the Ethereum Virtual Machine.
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/poseidon"
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+// PrecompiledContract is the basic interface for native Go contracts. The implementation
+// requires a deterministic gas count based on the input size of the Run method of the
+// contract.
+type PrecompiledContract interface {
+	RequiredGas(input []byte) uint64 // RequiredGas calculates the contract gas use
+	Run(input []byte) ([]byte, error) // Run runs the precompiled contract
+}
+
+// PrecompiledContractsPoseidon contains the precompiled contracts introduced for
+// zk-friendly hashing workloads. It is merged into the chain-rules-specific
+// precompile maps (e.g. PrecompiledContractsBerlin) by chains that opt in.
+var PrecompiledContractsPoseidon = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{10}): &poseidonHash{},
+}
+
+const (
+	// PoseidonBaseGas is the constant gas charged for any Poseidon call, covering
+	// the ABI decoding and field-membership checks done before hashing starts.
+	PoseidonBaseGas uint64 = 60
+	// PoseidonPerWordGas is charged per 32-byte field element absorbed into the sponge.
+	PoseidonPerWordGas uint64 = 12
+	// PoseidonPerRoundGas is charged per permutation round actually executed, so that
+	// wider states (which run more partial rounds, see poseidon.NROUNDSP) cost more.
+	PoseidonPerRoundGas uint64 = 6
+)
+
+var (
+	errPoseidonInputLength = errors.New("poseidon: input too short or misaligned")
+	errPoseidonWidth       = errors.New("poseidon: width out of range")
+	errPoseidonNotInField  = errors.New("poseidon: element not in BN254 scalar field")
+)
+
+// poseidonHash implements the Poseidon native contract exposed at address 0x0a.
+//
+// Input layout (all big-endian, 32-byte words, mirroring the modexp precompile):
+//
+//	[0:32]   width  - sponge width, 2 <= width <= len(poseidon.NROUNDSP)+1 (17)
+//	[32:64]  cap    - capacity/domain-separation flag fed into the sponge
+//	[64:]    elems  - zero or more 32-byte BN254 scalar field elements to absorb
+//
+// Output is the single 32-byte squeezed field element.
+type poseidonHash struct{}
+
+func (c *poseidonHash) parseInput(input []byte) (width int, cap int64, elems []*big.Int, err error) {
+	if len(input) < 64 || (len(input)-64)%32 != 0 {
+		return 0, 0, nil, errPoseidonInputLength
+	}
+	width = int(new(big.Int).SetBytes(input[0:32]).Int64())
+	if width < 2 || width-2 >= len(poseidon.NROUNDSP) {
+		return 0, 0, nil, errPoseidonWidth
+	}
+	cap = new(big.Int).SetBytes(input[32:64]).Int64()
+
+	n := (len(input) - 64) / 32
+	elems = make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		e := new(big.Int).SetBytes(input[64+i*32 : 64+(i+1)*32])
+		if !utils.CheckBigIntInField(e) {
+			return 0, 0, nil, errPoseidonNotInField
+		}
+		elems[i] = e
+	}
+	return width, cap, elems, nil
+}
+
+func (c *poseidonHash) RequiredGas(input []byte) uint64 {
+	width, _, elems, err := c.parseInput(input)
+	if err != nil {
+		// Charge the base cost even for malformed input; Run will reject it.
+		return PoseidonBaseGas
+	}
+	rate := width - 1
+	// blocks tracks the number of permutations HashWithCap actually runs: one
+	// per full-or-partial rate-sized chunk, and always at least one (even for
+	// zero elements, HashWithCap still permutes the empty-padded state once).
+	blocks := uint64(1)
+	if n := len(elems); n > 0 {
+		blocks = (uint64(n) + uint64(rate) - 1) / uint64(rate)
+	}
+	rounds := uint64(poseidon.NROUNDSF) + uint64(poseidon.NROUNDSP[width-2])
+	return PoseidonBaseGas + PoseidonPerWordGas*uint64(len(elems)) + PoseidonPerRoundGas*rounds*blocks
+}
+
+func (c *poseidonHash) Run(input []byte) ([]byte, error) {
+	width, cap, elems, err := c.parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	h, err := poseidon.HashWithCap(elems, width, cap)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 32)
+	h.FillBytes(out)
+	return out, nil
+}
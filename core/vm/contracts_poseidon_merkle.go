@@ -0,0 +1,91 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/poseidon/merkle"
+)
+
+// PrecompiledContractsPoseidonMerkle contains the Poseidon Merkle proof verifier,
+// exposed separately from PrecompiledContractsPoseidon so chains can opt into
+// either independently.
+var PrecompiledContractsPoseidonMerkle = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{11}): &poseidonMerkleVerify{},
+}
+
+const (
+	// PoseidonMerkleBaseGas covers ABI decoding before any hashing starts.
+	PoseidonMerkleBaseGas uint64 = 60
+	// PoseidonMerkleLevelGas is charged per tree level (one 2-to-1 Poseidon
+	// hash) walked while verifying the proof.
+	PoseidonMerkleLevelGas uint64 = 800
+)
+
+var (
+	errPoseidonMerkleInputLength = errors.New("poseidon merkle: input too short or misaligned")
+	errPoseidonMerkleDepth       = errors.New("poseidon merkle: sibling count does not match the declared depth")
+)
+
+// poseidonMerkleVerify implements the Poseidon Merkle proof verifier exposed at
+// address 0x0b.
+//
+// Input layout (big-endian 32-byte words):
+//
+//	[0:32]    depth    - the tree depth the caller is claiming; the proof
+//	                     must supply exactly this many siblings. Without this,
+//	                     an internal node several levels up is itself a valid
+//	                     Poseidon hash of a shallower subtree, so a truncated
+//	                     proof could be passed off as a full-depth one.
+//	[32:64]   root
+//	[64:96]   key
+//	[96:128]  value
+//	[128:]    siblings - exactly `depth` 32-byte sibling hashes, leaf level first
+//
+// Output is a single 32-byte word: 1 if the proof is valid, 0 otherwise.
+type poseidonMerkleVerify struct{}
+
+func (c *poseidonMerkleVerify) parseInput(input []byte) (depth int, root, key, value *big.Int, proof merkle.Proof, err error) {
+	if len(input) < 128 || (len(input)-128)%32 != 0 {
+		return 0, nil, nil, nil, merkle.Proof{}, errPoseidonMerkleInputLength
+	}
+	depth = int(new(big.Int).SetBytes(input[0:32]).Int64())
+	root = new(big.Int).SetBytes(input[32:64])
+	key = new(big.Int).SetBytes(input[64:96])
+	value = new(big.Int).SetBytes(input[96:128])
+
+	n := (len(input) - 128) / 32
+	if n != depth {
+		return 0, nil, nil, nil, merkle.Proof{}, errPoseidonMerkleDepth
+	}
+	siblings := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		siblings[i] = new(big.Int).SetBytes(input[128+i*32 : 128+(i+1)*32])
+	}
+	return depth, root, key, value, merkle.Proof{Siblings: siblings}, nil
+}
+
+func (c *poseidonMerkleVerify) RequiredGas(input []byte) uint64 {
+	if len(input) < 128 || (len(input)-128)%32 != 0 {
+		return PoseidonMerkleBaseGas
+	}
+	levels := uint64((len(input) - 128) / 32)
+	return PoseidonMerkleBaseGas + PoseidonMerkleLevelGas*levels
+}
+
+func (c *poseidonMerkleVerify) Run(input []byte) ([]byte, error) {
+	depth, root, key, value, proof, err := c.parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := merkle.VerifyProof(root, key, value, depth, proof)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 32)
+	if ok {
+		out[31] = 1
+	}
+	return out, nil
+}
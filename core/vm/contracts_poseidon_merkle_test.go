@@ -0,0 +1,106 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/poseidon/merkle"
+)
+
+// buildMerkleProof creates a depth-level sparse tree, writes value at key,
+// and returns the tree's root plus the proof for key - the fixture every
+// test below verifies the precompile's ABI encoding of.
+func buildMerkleProof(t *testing.T, depth int, key, value int64) (root *big.Int, proof merkle.Proof) {
+	t.Helper()
+	tr := merkle.NewSparseTree(depth)
+	if err := tr.Update(big.NewInt(key), big.NewInt(value)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	proof, err := tr.Prove(big.NewInt(key))
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	return tr.Root(), proof
+}
+
+func merkleInput(depth int, root, key, value *big.Int, proof merkle.Proof) []byte {
+	input := append([]byte{}, word32Big(big.NewInt(int64(depth)))...)
+	input = append(input, word32Big(root)...)
+	input = append(input, word32Big(key)...)
+	input = append(input, word32Big(value)...)
+	for _, s := range proof.Siblings {
+		input = append(input, word32Big(s)...)
+	}
+	return input
+}
+
+func word32Big(v *big.Int) []byte {
+	b := make([]byte, 32)
+	v.FillBytes(b)
+	return b
+}
+
+func TestPoseidonMerkleVerifyRun(t *testing.T) {
+	c := &poseidonMerkleVerify{}
+	root, proof := buildMerkleProof(t, 8, 5, 42)
+
+	out, err := c.Run(merkleInput(8, root, big.NewInt(5), big.NewInt(42), proof))
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	want := make([]byte, 32)
+	want[31] = 1
+	if !bytesEqual(out, want) {
+		t.Fatalf("Run output = %x, want %x (valid proof)", out, want)
+	}
+
+	out, err = c.Run(merkleInput(8, root, big.NewInt(5), big.NewInt(43), proof))
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !bytesEqual(out, make([]byte, 32)) {
+		t.Fatalf("Run output = %x, want all-zero (invalid proof)", out)
+	}
+}
+
+// TestPoseidonMerkleVerifyRunRejectsMalformedInput pins Run to reject input
+// that is too short or not a whole number of 32-byte sibling words, rather
+// than panicking on an out-of-bounds slice.
+func TestPoseidonMerkleVerifyRunRejectsMalformedInput(t *testing.T) {
+	c := &poseidonMerkleVerify{}
+
+	if _, err := c.Run(make([]byte, 127)); err != errPoseidonMerkleInputLength {
+		t.Fatalf("Run with too-short input: got %v, want errPoseidonMerkleInputLength", err)
+	}
+	if _, err := c.Run(make([]byte, 128+16)); err != errPoseidonMerkleInputLength {
+		t.Fatalf("Run with a misaligned sibling word: got %v, want errPoseidonMerkleInputLength", err)
+	}
+}
+
+// TestPoseidonMerkleVerifyRunRejectsDepthMismatch checks that Run rejects a
+// proof whose sibling count doesn't match the declared depth word, rather
+// than silently verifying a truncated proof against a shallower claim.
+func TestPoseidonMerkleVerifyRunRejectsDepthMismatch(t *testing.T) {
+	c := &poseidonMerkleVerify{}
+	root, proof := buildMerkleProof(t, 8, 5, 42)
+
+	if _, err := c.Run(merkleInput(4, root, big.NewInt(5), big.NewInt(42), proof)); err != errPoseidonMerkleDepth {
+		t.Fatalf("Run with mismatched depth: got %v, want errPoseidonMerkleDepth", err)
+	}
+}
+
+// TestPoseidonMerkleVerifyRequiredGas pins RequiredGas to the base cost plus
+// exactly one PoseidonMerkleLevelGas per sibling word supplied.
+func TestPoseidonMerkleVerifyRequiredGas(t *testing.T) {
+	c := &poseidonMerkleVerify{}
+
+	if got, want := c.RequiredGas(make([]byte, 127)), PoseidonMerkleBaseGas; got != want {
+		t.Fatalf("RequiredGas for malformed input: got %d, want %d", got, want)
+	}
+	if got, want := c.RequiredGas(make([]byte, 128)), PoseidonMerkleBaseGas; got != want {
+		t.Fatalf("RequiredGas for a zero-sibling proof: got %d, want %d", got, want)
+	}
+	if got, want := c.RequiredGas(make([]byte, 128+3*32)), PoseidonMerkleBaseGas+PoseidonMerkleLevelGas*3; got != want {
+		t.Fatalf("RequiredGas for a 3-sibling proof: got %d, want %d", got, want)
+	}
+}
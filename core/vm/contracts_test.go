@@ -0,0 +1,113 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/poseidon"
+)
+
+// word32 left-pads v into a 32-byte big-endian word, mirroring how callers
+// assemble poseidonHash's ABI-style input.
+func word32(v int64) []byte {
+	b := make([]byte, 32)
+	new(big.Int).SetInt64(v).FillBytes(b)
+	return b
+}
+
+func poseidonInput(width, cap int64, elems ...int64) []byte {
+	input := append([]byte{}, word32(width)...)
+	input = append(input, word32(cap)...)
+	for _, e := range elems {
+		input = append(input, word32(e)...)
+	}
+	return input
+}
+
+func TestPoseidonHashRun(t *testing.T) {
+	c := &poseidonHash{}
+	input := poseidonInput(3, 2, 1, 2)
+
+	out, err := c.Run(input)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(out) != 32 {
+		t.Fatalf("expected 32-byte output, got %d bytes", len(out))
+	}
+
+	want, err := poseidon.HashWithCap([]*big.Int{big.NewInt(1), big.NewInt(2)}, 3, 2)
+	if err != nil {
+		t.Fatalf("poseidon.HashWithCap: %v", err)
+	}
+	wantBytes := make([]byte, 32)
+	want.FillBytes(wantBytes)
+	if !bytesEqual(out, wantBytes) {
+		t.Fatalf("Run output does not match poseidon.HashWithCap: got %x, want %x", out, wantBytes)
+	}
+}
+
+// TestPoseidonHashWidthBounds pins the accepted width range to exactly
+// [2, len(poseidon.NROUNDSP)+1]. A previous off-by-one let width ==
+// len(poseidon.NROUNDSP)+2 through parseInput, which then panicked inside
+// poseidon.HashWithCap on the out-of-range NROUNDSP index.
+func TestPoseidonHashWidthBounds(t *testing.T) {
+	c := &poseidonHash{}
+	maxWidth := int64(len(poseidon.NROUNDSP) + 1)
+
+	if _, _, _, err := c.parseInput(poseidonInput(maxWidth, 0)); err != nil {
+		t.Fatalf("width %d should be accepted, got error: %v", maxWidth, err)
+	}
+	if _, _, _, err := c.parseInput(poseidonInput(maxWidth+1, 0)); err != errPoseidonWidth {
+		t.Fatalf("width %d should be rejected with errPoseidonWidth, got: %v", maxWidth+1, err)
+	}
+	if _, _, _, err := c.parseInput(poseidonInput(1, 0)); err != errPoseidonWidth {
+		t.Fatalf("width 1 should be rejected with errPoseidonWidth, got: %v", err)
+	}
+
+	// Run must never panic on the widths RequiredGas/parseInput reject.
+	if _, err := c.Run(poseidonInput(maxWidth+1, 0)); err != errPoseidonWidth {
+		t.Fatalf("Run with width %d should return errPoseidonWidth, got: %v", maxWidth+1, err)
+	}
+}
+
+// TestPoseidonHashRequiredGasBlocks pins RequiredGas to the number of
+// permutations HashWithCap actually performs: exactly one per rate-sized
+// chunk (at least one, even for zero elements), not one extra block whenever
+// the input length is a nonzero multiple of the rate.
+func TestPoseidonHashRequiredGasBlocks(t *testing.T) {
+	c := &poseidonHash{}
+	width := int64(3) // rate = 2
+
+	gasFor := func(n int) uint64 {
+		elems := make([]int64, n)
+		return c.RequiredGas(poseidonInput(width, 0, elems...))
+	}
+
+	roundGas := PoseidonPerRoundGas * (uint64(poseidon.NROUNDSF) + uint64(poseidon.NROUNDSP[width-2]))
+
+	// Zero elements still cost exactly one block's worth of rounds.
+	if got, want := gasFor(0), PoseidonBaseGas+roundGas; got != want {
+		t.Fatalf("gas for 0 elements: got %d, want %d", got, want)
+	}
+	// A full rate-sized chunk (2 elements) is still exactly one block.
+	if got, want := gasFor(2), PoseidonBaseGas+PoseidonPerWordGas*2+roundGas; got != want {
+		t.Fatalf("gas for 2 elements (one full block): got %d, want %d", got, want)
+	}
+	// One element past a full block starts a second block.
+	if got, want := gasFor(3), PoseidonBaseGas+PoseidonPerWordGas*3+roundGas*2; got != want {
+		t.Fatalf("gas for 3 elements (two blocks): got %d, want %d", got, want)
+	}
+}
+
+func bytesEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
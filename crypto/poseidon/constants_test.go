@@ -0,0 +1,101 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+
+	refposeidon "github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+// TestPoseidonConstantsShapes pins the generated round-constant and MDS
+// tables to the sizes fastPermute indexes into for every supported width -
+// this is what would have caught the series' build/runtime break (c was
+// referenced but never declared) before it shipped.
+func TestPoseidonConstantsShapes(t *testing.T) {
+	if len(c.c) != len(NROUNDSP) || len(c.m) != len(NROUNDSP) {
+		t.Fatalf("constant table width count = %d/%d, want %d", len(c.c), len(c.m), len(NROUNDSP))
+	}
+	for i, nRoundsP := range NROUNDSP {
+		width := i + 2
+		wantC := NROUNDSF*width + nRoundsP
+		if got := len(c.c[i]); got != wantC {
+			t.Errorf("width %d: len(c.c) = %d, want %d", width, got, wantC)
+		}
+		if got := len(c.m[i]); got != width {
+			t.Errorf("width %d: MDS matrix has %d rows, want %d", width, got, width)
+		}
+		for row, r := range c.m[i] {
+			if got := len(r); got != width {
+				t.Errorf("width %d: MDS matrix row %d has %d entries, want %d", width, row, got, width)
+			}
+		}
+	}
+}
+
+// TestPermuteRunsForEveryWidth exercises fastPermute (via Hash) across every
+// supported width so the constant tables are actually evaluated, not just
+// sized correctly, and checks the result is deterministic and depends on the
+// input.
+func TestPermuteRunsForEveryWidth(t *testing.T) {
+	for i := range NROUNDSP {
+		width := i + 2
+		elems := make([]*big.Int, width-1)
+		for j := range elems {
+			elems[j] = big.NewInt(int64(j + 1))
+		}
+
+		got, err := Hash(elems, width)
+		if err != nil {
+			t.Fatalf("width %d: Hash: %v", width, err)
+		}
+		again, err := Hash(elems, width)
+		if err != nil {
+			t.Fatalf("width %d: Hash (second call): %v", width, err)
+		}
+		if got.Cmp(again) != 0 {
+			t.Fatalf("width %d: Hash is not deterministic: %s vs %s", width, got, again)
+		}
+
+		elems[0] = big.NewInt(elems[0].Int64() + 1)
+		changed, err := Hash(elems, width)
+		if err != nil {
+			t.Fatalf("width %d: Hash with perturbed input: %v", width, err)
+		}
+		if got.Cmp(changed) == 0 {
+			t.Fatalf("width %d: changing the input did not change the hash", width)
+		}
+	}
+}
+
+// TestHashFixedAgainstReferenceImplementation cross-checks HashFixed against
+// github.com/iden3/go-iden3-crypto/poseidon, the reference BN254 Poseidon
+// implementation iden3's identity state trees and nullifiers are built from.
+// A mismatch here means the ported constants or round structure have
+// diverged from the real spec, not just an internal self-consistency bug.
+func TestHashFixedAgainstReferenceImplementation(t *testing.T) {
+	cases := [][]int64{
+		{1, 2},
+		{1},
+		{3, 4, 5, 6},
+		{0, 0},
+	}
+
+	for _, vals := range cases {
+		inp := make([]*big.Int, len(vals))
+		for i, v := range vals {
+			inp[i] = big.NewInt(v)
+		}
+
+		want, err := refposeidon.Hash(inp)
+		if err != nil {
+			t.Fatalf("%v: reference Hash returned error: %v", vals, err)
+		}
+		got, err := HashFixed(inp)
+		if err != nil {
+			t.Fatalf("%v: HashFixed returned error: %v", vals, err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatalf("%v: HashFixed = %s, want %s (from reference implementation)", vals, got, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package poseidon
+
+import "math/big"
+
+// Fr is a BN254 scalar-field element held as 4 little-endian uint64 limbs in
+// Montgomery form (the stored value is x*R mod p for R = 2^256 mod p). It
+// backs the fast permutation core in permute_fast.go: unlike *ff.Element,
+// arithmetic on Fr never touches the heap.
+//
+// mulFr/addFr are implemented in hand-written amd64 assembly (fr_amd64.s);
+// other architectures fall back to the math/bits version in fr_generic.go.
+type Fr [4]uint64
+
+// frModulus is the BN254 scalar field modulus, little-endian limbs.
+var frModulus = Fr{0x43e1f593f0000001, 0x2833e84879b97091, 0xb85045b68181585d, 0x30644e72e131a029}
+
+// frR2 is R^2 mod p, used to lift a plain integer into Montgomery form.
+var frR2 = Fr{0x1bb8e645ae216da7, 0x53fe3ab1e35c59e3, 0x8c49833d53bb8085, 0x216d0b17f4e44a5}
+
+// frN0Inv is -p^-1 mod 2^64, the constant the CIOS reduction multiplies by.
+const frN0Inv uint64 = 0xc2e1f593efffffff
+
+var frModulusBig, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10) //nolint:lll
+
+// FrFromBigInt reduces v mod p and lifts it into Montgomery form.
+func FrFromBigInt(v *big.Int) Fr {
+	m := new(big.Int).Mod(v, frModulusBig)
+	var buf [32]byte
+	m.FillBytes(buf[:])
+
+	var raw Fr
+	for i := 0; i < 4; i++ {
+		for b := 0; b < 8; b++ {
+			raw[i] |= uint64(buf[31-(i*8+b)]) << (8 * b)
+		}
+	}
+	return mulFr(raw, frR2)
+}
+
+// BigInt converts x out of Montgomery form back into a plain big.Int.
+func (x Fr) BigInt() *big.Int {
+	plain := mulFr(x, Fr{1, 0, 0, 0})
+	var buf [32]byte
+	for i := 0; i < 4; i++ {
+		for b := 0; b < 8; b++ {
+			buf[31-(i*8+b)] = byte(plain[i] >> (8 * b))
+		}
+	}
+	return new(big.Int).SetBytes(buf[:])
+}
@@ -0,0 +1,21 @@
+//go:build amd64
+
+package poseidon
+
+//go:noescape
+func mulFrAsm(z, x, y *Fr)
+
+//go:noescape
+func addFrAsm(z, x, y *Fr)
+
+func mulFr(x, y Fr) Fr {
+	var z Fr
+	mulFrAsm(&z, &x, &y)
+	return z
+}
+
+func addFr(x, y Fr) Fr {
+	var z Fr
+	addFrAsm(&z, &x, &y)
+	return z
+}
@@ -0,0 +1,30 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/ff"
+)
+
+func BenchmarkMulFr(b *testing.B) {
+	x := FrFromBigInt(big.NewInt(12345678901234))
+	y := FrFromBigInt(big.NewInt(98765432109876))
+	for i := 0; i < b.N; i++ {
+		x = mulFr(x, y)
+	}
+}
+
+// BenchmarkPermuteWidth3 measures the width-3 permutation (two absorbed
+// elements plus the capacity slot) used by the common Hash(..., 3) case.
+func BenchmarkPermuteWidth3(b *testing.B) {
+	state := []*ff.Element{
+		ff.NewElement().SetBigInt(big.NewInt(0)),
+		ff.NewElement().SetBigInt(big.NewInt(1)),
+		ff.NewElement().SetBigInt(big.NewInt(2)),
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		permute(state, 3)
+	}
+}
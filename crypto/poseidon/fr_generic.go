@@ -0,0 +1,87 @@
+//go:build !amd64
+
+package poseidon
+
+import "math/bits"
+
+// frLess reports whether a < b as 256-bit unsigned integers.
+func frLess(a, b Fr) bool {
+	for i := 3; i >= 0; i-- {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// frSub returns a-b, assuming a >= b.
+func frSub(a, b Fr) Fr {
+	var z Fr
+	var borrow uint64
+	for i := 0; i < 4; i++ {
+		d, bw := bits.Sub64(a[i], b[i], borrow)
+		z[i] = d
+		borrow = bw
+	}
+	return z
+}
+
+// addFr returns (x+y) mod p.
+func addFr(x, y Fr) Fr {
+	var z Fr
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		s, c := bits.Add64(x[i], y[i], carry)
+		z[i] = s
+		carry = c
+	}
+	if carry != 0 || !frLess(z, frModulus) {
+		z = frSub(z, frModulus)
+	}
+	return z
+}
+
+// mulFr returns the Montgomery product of x and y, i.e. if x=a*R and y=b*R
+// then mulFr(x,y) = a*b*R mod p, via the CIOS reduction algorithm.
+func mulFr(x, y Fr) Fr {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c1 := bits.Add64(lo, t[j], 0)
+			hi, _ = bits.Add64(hi, 0, c1)
+			lo, c2 := bits.Add64(lo, carry, 0)
+			hi, _ = bits.Add64(hi, 0, c2)
+			t[j] = lo
+			carry = hi
+		}
+		s, c := bits.Add64(t[4], carry, 0)
+		t[4] = s
+		t[5] = c
+
+		m := t[0] * frN0Inv
+		hi, lo := bits.Mul64(m, frModulus[0])
+		_, c1 := bits.Add64(lo, t[0], 0)
+		carry, _ = bits.Add64(hi, 0, c1)
+		for j := 1; j < 4; j++ {
+			hi, lo := bits.Mul64(m, frModulus[j])
+			lo, c1 := bits.Add64(lo, t[j], 0)
+			hi, _ = bits.Add64(hi, 0, c1)
+			lo, c2 := bits.Add64(lo, carry, 0)
+			hi, _ = bits.Add64(hi, 0, c2)
+			t[j-1] = lo
+			carry = hi
+		}
+		s, c = bits.Add64(t[4], carry, 0)
+		t[3] = s
+		t[4] = t[5] + c
+		t[5] = 0
+	}
+
+	z := Fr{t[0], t[1], t[2], t[3]}
+	if t[4] != 0 || !frLess(z, frModulus) {
+		z = frSub(z, frModulus)
+	}
+	return z
+}
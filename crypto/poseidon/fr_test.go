@@ -0,0 +1,53 @@
+package poseidon
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestFrRoundTrip(t *testing.T) {
+	for _, dec := range []string{
+		"0",
+		"1",
+		"2",
+		"21888242871839275222246405745257275088548364400416034343698204186575808495616", // p-1
+		"21888242871839275222246405745257275088548364400416034343698204186575808495617", // p, reduces to 0
+		"123456789012345678901234567890123456789012345678901234567890",
+	} {
+		v, ok := new(big.Int).SetString(dec, 10)
+		if !ok {
+			t.Fatalf("bad test value %q", dec)
+		}
+		want := new(big.Int).Mod(v, frModulusBig)
+		got := FrFromBigInt(v).BigInt()
+		if got.Cmp(want) != 0 {
+			t.Errorf("round-trip %s: got %s, want %s", dec, got, want)
+		}
+	}
+}
+
+func TestFrAddMulAgainstBigInt(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randFieldElement := func() *big.Int {
+		b := make([]byte, 32)
+		rng.Read(b)
+		return new(big.Int).Mod(new(big.Int).SetBytes(b), frModulusBig)
+	}
+
+	for i := 0; i < 20000; i++ {
+		a := randFieldElement()
+		b := randFieldElement()
+		fa, fb := FrFromBigInt(a), FrFromBigInt(b)
+
+		wantSum := new(big.Int).Mod(new(big.Int).Add(a, b), frModulusBig)
+		if gotSum := addFr(fa, fb).BigInt(); gotSum.Cmp(wantSum) != 0 {
+			t.Fatalf("addFr(%s, %s) = %s, want %s", a, b, gotSum, wantSum)
+		}
+
+		wantProd := new(big.Int).Mod(new(big.Int).Mul(a, b), frModulusBig)
+		if gotProd := mulFr(fa, fb).BigInt(); gotProd.Cmp(wantProd) != 0 {
+			t.Fatalf("mulFr(%s, %s) = %s, want %s", a, b, gotProd, wantProd)
+		}
+	}
+}
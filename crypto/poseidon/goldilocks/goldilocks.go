@@ -0,0 +1,210 @@
+// Package goldilocks implements the Poseidon permutation over the Goldilocks
+// field (p = 2^64 - 2^32 + 1), the hash used by Plonky2 and Polygon zkEVM.
+// Unlike the BN254 variant in the parent poseidon package, Goldilocks
+// arithmetic fits entirely in a native uint64 with cheap reductions, which
+// makes this variant considerably faster at the cost of a fixed width-12
+// state.
+//
+// The round constants, MDS matrix, and partial-round "sparse matrix"
+// optimization below are ported from the reference implementation in
+// github.com/iden3/go-iden3-crypto/goldenposeidon (the same constants
+// Plonky2 and Polygon zkEVM use), not re-derived - Poseidon's security proof
+// is about a specific set of constants, and a differently-generated set
+// produces a hash that merely looks like Poseidon without being compatible
+// with any real prover.
+package goldilocks
+
+import "math/bits"
+
+const (
+	// Prime is the Goldilocks field modulus 2^64 - 2^32 + 1.
+	Prime uint64 = 0xFFFFFFFF00000001
+	// epsilon is 2^64 mod Prime, i.e. 2^32 - 1. It falls out of the shape of
+	// Prime and is what makes reduction cheap: carries out of a 64-bit
+	// add/mul can be folded back in by a multiply-by-epsilon instead of a
+	// full division.
+	epsilon uint64 = 0xFFFFFFFF
+
+	// Width is the sponge state size used by this variant.
+	Width = 12
+	// FullRounds is the number of full S-box rounds, split evenly before and
+	// after the partial rounds.
+	FullRounds = 8
+	// PartialRounds is the number of partial (single S-box) rounds.
+	PartialRounds = 22
+)
+
+// addMod returns (a+b) mod Prime for a, b < Prime.
+func addMod(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	sum, _ = bits.Add64(sum, carry*epsilon, 0)
+	if sum >= Prime {
+		sum -= Prime
+	}
+	return sum
+}
+
+// subMod returns (a-b) mod Prime for a, b < Prime.
+func subMod(a, b uint64) uint64 {
+	diff, borrow := bits.Sub64(a, b, 0)
+	if borrow != 0 {
+		diff -= epsilon
+	}
+	return diff
+}
+
+// reduce128 reduces a 128-bit product (hi:lo) modulo Prime.
+func reduce128(hi, lo uint64) uint64 {
+	hiHi := hi >> 32
+	hiLo := hi & epsilon
+
+	t0, borrow := bits.Sub64(lo, hiHi, 0)
+	if borrow != 0 {
+		t0 -= epsilon
+	}
+	t1 := hiLo * epsilon
+	return addMod(t0, t1)
+}
+
+// mulMod returns (a*b) mod Prime for a, b < Prime.
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return reduce128(hi, lo)
+}
+
+// sbox computes x^7 mod Prime, the S-box degree used for Goldilocks since
+// neither 3 nor 5 is coprime with Prime-1.
+func sbox(x uint64) uint64 {
+	x2 := mulMod(x, x)
+	x4 := mulMod(x2, x2)
+	x6 := mulMod(x4, x2)
+	return mulMod(x6, x)
+}
+
+// mCirc and mDiag describe the MDS matrix applied after every full round and
+// after the partial rounds: mMatrix[i][j] is the circulant mCirc[(i-j)%Width],
+// except on the diagonal where mDiag is added in.
+var (
+	mCirc = [Width]uint64{17, 15, 41, 16, 2, 28, 13, 13, 39, 18, 34, 20}
+	mDiag = [Width]uint64{8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	mMatrix [Width][Width]uint64
+)
+
+func init() {
+	for i := 0; i < Width; i++ {
+		for j := 0; j < Width; j++ {
+			v := mCirc[(i-j+Width)%Width]
+			if i == j {
+				v = mCirc[0] + mDiag[i]
+			}
+			mMatrix[i][j] = v
+		}
+	}
+}
+
+// mix returns matrix^T * state, i.e. out[i] = sum_j matrix[j][i]*state[j].
+// Passing useP selects the pMatrix used for the one mix step that transitions
+// from full rounds into the optimized partial-round loop below; every other
+// mix step uses the plain MDS matrix mMatrix.
+func mix(state [Width]uint64, useP bool) [Width]uint64 {
+	var out [Width]uint64
+	for i := 0; i < Width; i++ {
+		var acc uint64
+		for j := 0; j < Width; j++ {
+			coeff := mMatrix[j][i]
+			if useP {
+				coeff = pMatrix[j][i]
+			}
+			acc = addMod(acc, mulMod(coeff, state[j]))
+		}
+		out[i] = acc
+	}
+	return out
+}
+
+// Permute runs the full Goldilocks Poseidon permutation over a width-12
+// state and returns the result; the input is left unmodified.
+//
+// The partial rounds use the standard "sparse matrix" optimization: instead
+// of a full Width x Width mix, only state[0] mixes with every other element
+// (via sparseMatrix), which is equivalent to the full MDS matrix for this
+// specific pMatrix/sparseMatrix pair but far cheaper per round.
+func Permute(state [Width]uint64) [Width]uint64 {
+	s := state
+
+	for i := 0; i < Width; i++ {
+		s[i] = addMod(s[i], roundConstants[i])
+	}
+
+	for r := 0; r < FullRounds/2; r++ {
+		for i := range s {
+			s[i] = sbox(s[i])
+		}
+		for i := range s {
+			s[i] = addMod(s[i], roundConstants[(r+1)*Width+i])
+		}
+		s = mix(s, r == FullRounds/2-1)
+	}
+
+	for r := 0; r < PartialRounds; r++ {
+		s[0] = sbox(s[0])
+		s[0] = addMod(s[0], roundConstants[(FullRounds/2+1)*Width+r])
+
+		var s0 uint64
+		s0 = addMod(s0, mulMod(sparseMatrix[(Width*2-1)*r], s[0]))
+		for i := 1; i < Width; i++ {
+			s0 = addMod(s0, mulMod(sparseMatrix[(Width*2-1)*r+i], s[i]))
+			s[i] = addMod(s[i], mulMod(sparseMatrix[(Width*2-1)*r+Width+i-1], s[0]))
+		}
+		s[0] = s0
+	}
+
+	for r := 0; r < FullRounds/2; r++ {
+		for i := range s {
+			s[i] = sbox(s[i])
+		}
+		if r < FullRounds/2-1 {
+			for i := range s {
+				s[i] = addMod(s[i], roundConstants[(FullRounds/2+1+r)*Width+PartialRounds+i])
+			}
+		}
+		s = mix(s, false)
+	}
+
+	return s
+}
+
+// Hash sponges an arbitrary number of field elements (each reduced mod
+// Prime) down to a 4-element digest, using a rate of Width-4 and a capacity
+// of 4. The number of absorbed elements is written into the capacity portion
+// of the state before the first permutation, the same way poseidon.HashWithCap
+// seeds its capacity slot with len(inpBI): without it, inputs of different
+// lengths that happen to share a prefix (e.g. a short input versus that same
+// input padded out to a full rate block) would hash identically.
+func Hash(input []uint64) [4]uint64 {
+	const rate = Width - 4
+
+	var state [Width]uint64
+	state[rate] = uint64(len(input)) % Prime
+
+	remaining := input
+	for {
+		n := rate
+		if len(remaining) < n {
+			n = len(remaining)
+		}
+		for i := 0; i < n; i++ {
+			state[i] = addMod(state[i], remaining[i]%Prime)
+		}
+		state = Permute(state)
+		remaining = remaining[n:]
+		if len(remaining) == 0 {
+			break
+		}
+	}
+
+	var out [4]uint64
+	copy(out[:], state[:4])
+	return out
+}
@@ -0,0 +1,88 @@
+package goldilocks
+
+import (
+	"testing"
+
+	goldenposeidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+)
+
+// TestPermuteAgainstReferenceImplementation cross-checks Permute against
+// github.com/iden3/go-iden3-crypto/goldenposeidon, the reference Goldilocks
+// Poseidon implementation Plonky2/Polygon zkEVM state roots are built from.
+// A mismatch here means the ported constants or round structure have
+// diverged from the real spec, not just an internal self-consistency bug.
+func TestPermuteAgainstReferenceImplementation(t *testing.T) {
+	cases := [][2][]uint64{
+		{
+			{0, 0, 0, 0, 0, 0, 0, 0},
+			{0, 0, 0, 0},
+		},
+		{
+			{1, 2, 3, 4, 5, 6, 7, 8},
+			{0, 0, 0, 0},
+		},
+		{
+			{1, 2, 3, 4, 5, 6, 7, 8},
+			{8, 0, 0, 0},
+		},
+		{
+			{0xFFFFFFFF00000000, 0x1, 0xFFFFFFFEFFFFFFFF, 42, 0, 0, 0, 0},
+			{1, 2, 3, 4},
+		},
+	}
+
+	for i, c := range cases {
+		var input [8]uint64
+		copy(input[:], c[0])
+		var cap [4]uint64
+		copy(cap[:], c[1])
+
+		want, err := goldenposeidon.Hash(input, cap)
+		if err != nil {
+			t.Fatalf("case %d: reference Hash returned error: %v", i, err)
+		}
+
+		var state [Width]uint64
+		copy(state[:8], input[:])
+		copy(state[8:], cap[:])
+		got := Permute(state)
+
+		for j := 0; j < 4; j++ {
+			if got[j] != want[j] {
+				t.Fatalf("case %d: Permute()[%d] = %#x, want %#x (from reference implementation)", i, j, got[j], want[j])
+			}
+		}
+	}
+}
+
+// TestHashLengthDomainSeparation guards against the collision a missing
+// length/capacity encoding would allow: a short input padded out with zeros
+// to a full rate block must not hash the same as the short input itself.
+func TestHashLengthDomainSeparation(t *testing.T) {
+	short := Hash([]uint64{0})
+	padded := Hash(make([]uint64, Width-4))
+	if short == padded {
+		t.Fatalf("Hash([0]) == Hash(zeros of length rate): %x", short)
+	}
+
+	empty := Hash(nil)
+	zeroState := [4]uint64{}
+	if empty == zeroState {
+		t.Fatalf("Hash(nil) returned the unpermuted zero state: %x", empty)
+	}
+}
+
+// TestHashMultiBlock exercises more than one absorb/permute cycle.
+func TestHashMultiBlock(t *testing.T) {
+	const rate = Width - 4
+	oneBlock := make([]uint64, rate)
+	twoBlocks := make([]uint64, rate+1)
+	for i := range twoBlocks {
+		twoBlocks[i] = uint64(i + 1)
+	}
+	copy(oneBlock, twoBlocks[:rate])
+
+	if Hash(oneBlock) == Hash(twoBlocks) {
+		t.Fatalf("Hash of a single full block collided with Hash of that block plus one more element")
+	}
+}
@@ -0,0 +1,177 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+// hashStructWidth is the sponge width HashStruct absorbs typed fields with.
+// A wider state (larger rate) means fewer permutations for payloads with
+// many fields.
+const hashStructWidth = 16
+
+// domainChunkBytes is how many bytes of the domain string are packed into a
+// single field element; 31 keeps every chunk safely under the 254-bit BN254
+// scalar field regardless of byte values.
+const domainChunkBytes = 31
+
+// fieldTypeTag identifies the wire type flattenTypedValue reduced a field
+// value to. It is absorbed alongside every field's name and value so that,
+// say, a uint256 and a bytes32 holding the same number don't flatten to the
+// same element sequence.
+type fieldTypeTag int64
+
+const (
+	tagUint256 fieldTypeTag = iota + 1
+	tagAddress
+	tagBytes32
+	tagArray
+	tagStruct
+)
+
+// HashStruct canonicalizes a typed payload into a deterministic sequence of
+// field elements and feeds it through a Poseidon sponge domain-separated by
+// poseidon(domain), mirroring how EIP-712 layers typed hashing on top of
+// keccak but producing a zk-friendly digest (e.g. for BabyJubJub/EdDSA
+// signing over iden3-style credentials). As in EIP-712, every field's name
+// and declared type are absorbed alongside its value, so two payloads that
+// differ only in field names/types (with identical values in the same sort
+// order) still hash differently.
+//
+// Supported field value types, recursively:
+//
+//	*big.Int       - uint256
+//	[20]byte       - address
+//	[32]byte       - bytes32
+//	[]any          - array of any supported type
+//	map[string]any - nested struct
+//
+// Fields are absorbed in ascending key order so the result only depends on
+// the payload's contents, not on map iteration or construction order.
+func HashStruct(domain string, typed map[string]any) (*big.Int, error) {
+	domainElems := bytesToFieldElements([]byte(domain))
+	domainHash, err := Hash(domainElems, 3)
+	if err != nil {
+		return nil, fmt.Errorf("poseidon: hashing domain separator: %w", err)
+	}
+
+	// domainHash is a full ~254-bit field element; absorb it as sponge input
+	// rather than truncating it into the small int64 capacity flag, which
+	// would throw away almost all of its entropy.
+	sp := NewSponge(hashStructWidth, 0)
+	if err := sp.Absorb(domainHash); err != nil {
+		return nil, fmt.Errorf("poseidon: absorbing domain separator: %w", err)
+	}
+	if err := absorbTypedFields(sp, typed); err != nil {
+		return nil, err
+	}
+	return sp.Squeeze(), nil
+}
+
+// bytesToFieldElements packs b into a length-prefixed sequence of
+// domainChunkBytes-sized big-endian chunks: the first element is len(b), so
+// that leading zero bytes - which big.Int.SetBytes otherwise drops silently
+// - change the prefix instead of vanishing. Without it, "A" and "\x00A" pack
+// to the same single chunk (both SetBytes to 65) and hash identically.
+func bytesToFieldElements(b []byte) []*big.Int {
+	elems := make([]*big.Int, 0, 1+(len(b)+domainChunkBytes-1)/domainChunkBytes)
+	elems = append(elems, big.NewInt(int64(len(b))))
+	for len(b) > 0 {
+		n := domainChunkBytes
+		if len(b) < n {
+			n = len(b)
+		}
+		elems = append(elems, new(big.Int).SetBytes(b[:n]))
+		b = b[n:]
+	}
+	return elems
+}
+
+// hashTypedFields sorts typed by key and squeezes its name+type+value
+// elements out of a fresh sponge. Used both as the top-level field loop (via
+// absorbTypedFields) and, recursively, to fold a nested struct field down to
+// a single element.
+func hashTypedFields(typed map[string]any) (*big.Int, error) {
+	sp := NewSponge(hashStructWidth, 0)
+	if err := absorbTypedFields(sp, typed); err != nil {
+		return nil, err
+	}
+	return sp.Squeeze(), nil
+}
+
+// absorbTypedFields absorbs, for every field in ascending key order, the
+// field name (as chunked bytes), its fieldTypeTag, and its flattened value
+// elements, in that order.
+func absorbTypedFields(sp *Sponge, typed map[string]any) error {
+	keys := make([]string, 0, len(typed))
+	for k := range typed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		tag, elems, err := flattenTypedValue(typed[k])
+		if err != nil {
+			return fmt.Errorf("poseidon: field %q: %w", k, err)
+		}
+		if err := sp.AbsorbMany(bytesToFieldElements([]byte(k))); err != nil {
+			return fmt.Errorf("poseidon: field %q: %w", k, err)
+		}
+		if err := sp.Absorb(big.NewInt(int64(tag))); err != nil {
+			return fmt.Errorf("poseidon: field %q: %w", k, err)
+		}
+		if err := sp.AbsorbMany(elems); err != nil {
+			return fmt.Errorf("poseidon: field %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// flattenTypedValue reduces a single typed field value down to its
+// fieldTypeTag and the sequence of field elements it contributes to the
+// sponge.
+func flattenTypedValue(v any) (fieldTypeTag, []*big.Int, error) {
+	switch val := v.(type) {
+	case *big.Int:
+		if !utils.CheckBigIntInField(val) {
+			return 0, nil, errors.New("uint256 value not inside the BN254 scalar field")
+		}
+		return tagUint256, []*big.Int{val}, nil
+
+	case [20]byte:
+		return tagAddress, []*big.Int{new(big.Int).SetBytes(val[:])}, nil
+
+	case [32]byte:
+		e := new(big.Int).SetBytes(val[:])
+		if !utils.CheckBigIntInField(e) {
+			return 0, nil, errors.New("bytes32 value not inside the BN254 scalar field")
+		}
+		return tagBytes32, []*big.Int{e}, nil
+
+	case []any:
+		elems := make([]*big.Int, 0, 2*len(val))
+		for i, item := range val {
+			tag, sub, err := flattenTypedValue(item)
+			if err != nil {
+				return 0, nil, fmt.Errorf("array element %d: %w", i, err)
+			}
+			elems = append(elems, big.NewInt(int64(tag)))
+			elems = append(elems, sub...)
+		}
+		return tagArray, elems, nil
+
+	case map[string]any:
+		h, err := hashTypedFields(val)
+		if err != nil {
+			return 0, nil, err
+		}
+		return tagStruct, []*big.Int{h}, nil
+
+	default:
+		return 0, nil, fmt.Errorf("unsupported typed field value of type %T", v)
+	}
+}
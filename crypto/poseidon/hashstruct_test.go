@@ -0,0 +1,118 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestHashStructFieldNameAffectsHash(t *testing.T) {
+	a, err := HashStruct("d", map[string]any{"amount": big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	b, err := HashStruct("d", map[string]any{"value": big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if a.Cmp(b) == 0 {
+		t.Fatalf("fields with different names but the same value hashed identically: %s", a)
+	}
+}
+
+func TestHashStructFieldTypeAffectsHash(t *testing.T) {
+	var asBytes32 [32]byte
+	big.NewInt(42).FillBytes(asBytes32[:])
+
+	a, err := HashStruct("d", map[string]any{"x": big.NewInt(42)})
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	b, err := HashStruct("d", map[string]any{"x": asBytes32})
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if a.Cmp(b) == 0 {
+		t.Fatalf("a uint256 and a bytes32 holding the same numeric value hashed identically: %s", a)
+	}
+}
+
+func TestHashStructDomainSeparation(t *testing.T) {
+	payload := map[string]any{"x": big.NewInt(1)}
+	a, err := HashStruct("domain-a", payload)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	b, err := HashStruct("domain-b", payload)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if a.Cmp(b) == 0 {
+		t.Fatalf("different domains produced the same hash: %s", a)
+	}
+}
+
+// TestHashStructLeadingZeroByteAffectsHash checks that a domain or field name
+// with a leading 0x00 byte doesn't collide with the same string minus that
+// byte - bytesToFieldElements must not let big.Int.SetBytes silently drop it.
+func TestHashStructLeadingZeroByteAffectsHash(t *testing.T) {
+	payload := map[string]any{"x": big.NewInt(1)}
+
+	a, err := HashStruct("d", payload)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	b, err := HashStruct("\x00d", payload)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if a.Cmp(b) == 0 {
+		t.Fatalf("domains %q and %q hashed identically: %s", "d", "\x00d", a)
+	}
+
+	c, err := HashStruct("d", map[string]any{"\x00x": big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if a.Cmp(c) == 0 {
+		t.Fatalf("field names %q and %q hashed identically: %s", "x", "\x00x", a)
+	}
+}
+
+func TestHashStructNestedAndArray(t *testing.T) {
+	payload := map[string]any{
+		"owner": [20]byte{1, 2, 3},
+		"items": []any{big.NewInt(1), big.NewInt(2)},
+		"meta": map[string]any{
+			"nonce": big.NewInt(7),
+		},
+	}
+	h1, err := HashStruct("d", payload)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+
+	reordered := map[string]any{
+		"meta": map[string]any{
+			"nonce": big.NewInt(7),
+		},
+		"items": []any{big.NewInt(1), big.NewInt(2)},
+		"owner": [20]byte{1, 2, 3},
+	}
+	h2, err := HashStruct("d", reordered)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if h1.Cmp(h2) != 0 {
+		t.Fatalf("field insertion order changed the hash: %s vs %s", h1, h2)
+	}
+
+	swapped := []any{big.NewInt(2), big.NewInt(1)}
+	payload["items"] = swapped
+	h3, err := HashStruct("d", payload)
+	if err != nil {
+		t.Fatalf("HashStruct: %v", err)
+	}
+	if h1.Cmp(h3) == 0 {
+		t.Fatalf("reordering array elements did not change the hash")
+	}
+}
@@ -0,0 +1,216 @@
+// Package merkle implements Poseidon-backed Merkle trees: a key/value sparse
+// Merkle tree and an incremental append-only Merkle tree, both using width-3
+// Poseidon (2-to-1 hashing) as the node hash. This lets consumers build
+// iden3-style identity state and nullifier trees directly against this module.
+package merkle
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/poseidon"
+)
+
+// Proof is an inclusion/exclusion proof: the list of sibling hashes along the
+// path from a leaf to the root, ordered from the leaf level upwards.
+type Proof struct {
+	Siblings []*big.Int
+}
+
+func hash2(l, r *big.Int) (*big.Int, error) {
+	return poseidon.HashFixed([]*big.Int{l, r})
+}
+
+func computeZeroes(depth int) []*big.Int {
+	zeroes := make([]*big.Int, depth+1)
+	zeroes[0] = big.NewInt(0)
+	for i := 1; i <= depth; i++ {
+		h, err := hash2(zeroes[i-1], zeroes[i-1])
+		if err != nil {
+			// zeroes are always in-field small integers, this cannot fail.
+			panic(err)
+		}
+		zeroes[i] = h
+	}
+	return zeroes
+}
+
+// SparseTree is a key/value sparse Merkle tree of fixed depth, addressed by
+// the low `depth` bits of the key.
+type SparseTree struct {
+	depth  int
+	zeroes []*big.Int
+	nodes  map[string]*big.Int
+	leaves map[string]*big.Int
+	root   *big.Int
+}
+
+// NewSparseTree creates an empty sparse Merkle tree with the given depth.
+func NewSparseTree(depth int) *SparseTree {
+	zeroes := computeZeroes(depth)
+	return &SparseTree{
+		depth:  depth,
+		zeroes: zeroes,
+		nodes:  make(map[string]*big.Int),
+		leaves: make(map[string]*big.Int),
+		root:   zeroes[depth],
+	}
+}
+
+// Root returns the current root hash of the tree.
+func (t *SparseTree) Root() *big.Int {
+	return t.root
+}
+
+func nodeKey(level int, pos *big.Int) string {
+	return fmt.Sprintf("%d:%s", level, pos.String())
+}
+
+func (t *SparseTree) nodeAt(level int, pos *big.Int) *big.Int {
+	if v, ok := t.nodes[nodeKey(level, pos)]; ok {
+		return v
+	}
+	return t.zeroes[level]
+}
+
+func (t *SparseTree) setNode(level int, pos *big.Int, v *big.Int) {
+	t.nodes[nodeKey(level, pos)] = v
+}
+
+// Update inserts or overwrites the value stored at key, recomputing the path
+// to the root.
+func (t *SparseTree) Update(key, value *big.Int) error {
+	if key.Sign() < 0 || key.BitLen() > t.depth {
+		return fmt.Errorf("merkle: key out of range for depth %d", t.depth)
+	}
+	cur := value
+	for level := 0; level < t.depth; level++ {
+		pos := new(big.Int).Rsh(key, uint(level))
+		sibling := t.nodeAt(level, new(big.Int).Xor(pos, big.NewInt(1)))
+
+		t.setNode(level, pos, cur)
+
+		var l, r *big.Int
+		if pos.Bit(0) == 0 {
+			l, r = cur, sibling
+		} else {
+			l, r = sibling, cur
+		}
+		parent, err := hash2(l, r)
+		if err != nil {
+			return err
+		}
+		cur = parent
+	}
+	t.leaves[key.String()] = value
+	t.root = cur
+	return nil
+}
+
+// Get returns the value stored at key, if any.
+func (t *SparseTree) Get(key *big.Int) (*big.Int, bool) {
+	v, ok := t.leaves[key.String()]
+	return v, ok
+}
+
+// Prove returns the inclusion proof for key as it currently stands in the
+// tree (an exclusion proof if no value was ever set at key).
+func (t *SparseTree) Prove(key *big.Int) (Proof, error) {
+	if key.Sign() < 0 || key.BitLen() > t.depth {
+		return Proof{}, fmt.Errorf("merkle: key out of range for depth %d", t.depth)
+	}
+	siblings := make([]*big.Int, t.depth)
+	for level := 0; level < t.depth; level++ {
+		pos := new(big.Int).Rsh(key, uint(level))
+		siblings[level] = t.nodeAt(level, new(big.Int).Xor(pos, big.NewInt(1)))
+	}
+	return Proof{Siblings: siblings}, nil
+}
+
+// VerifyProof checks that value is included at key under root, for a tree of
+// the given depth, given proof.
+//
+// depth must match len(proof.Siblings) exactly: an internal node produced by
+// a deeper tree is itself a valid Poseidon hash of a shorter subtree, so
+// without pinning the depth a truncated proof could be presented against
+// that internal value passed in as root and verify successfully.
+func VerifyProof(root, key, value *big.Int, depth int, proof Proof) (bool, error) {
+	if len(proof.Siblings) != depth {
+		return false, fmt.Errorf("merkle: proof has %d siblings, want %d for depth %d", len(proof.Siblings), depth, depth)
+	}
+	cur := value
+	for level, sibling := range proof.Siblings {
+		pos := new(big.Int).Rsh(key, uint(level))
+		var l, r *big.Int
+		if pos.Bit(0) == 0 {
+			l, r = cur, sibling
+		} else {
+			l, r = sibling, cur
+		}
+		h, err := hash2(l, r)
+		if err != nil {
+			return false, err
+		}
+		cur = h
+	}
+	return cur.Cmp(root) == 0, nil
+}
+
+// AppendTree is an incremental append-only Merkle tree of fixed depth: leaves
+// are always added at the next free index, and intermediate "filled subtree"
+// hashes are cached so each Append only recomputes the path to the root.
+type AppendTree struct {
+	depth          int
+	zeroes         []*big.Int
+	filledSubtrees []*big.Int
+	count          int
+	root           *big.Int
+}
+
+// NewAppendTree creates an empty append-only Merkle tree with the given depth.
+func NewAppendTree(depth int) *AppendTree {
+	zeroes := computeZeroes(depth)
+	filled := make([]*big.Int, depth)
+	copy(filled, zeroes[:depth])
+	return &AppendTree{
+		depth:          depth,
+		zeroes:         zeroes,
+		filledSubtrees: filled,
+		root:           zeroes[depth],
+	}
+}
+
+// Root returns the current root hash of the tree.
+func (t *AppendTree) Root() *big.Int {
+	return t.root
+}
+
+// Append inserts value at the next free leaf index and returns that index.
+func (t *AppendTree) Append(value *big.Int) (int, error) {
+	if t.count >= 1<<uint(t.depth) {
+		return 0, fmt.Errorf("merkle: append tree at capacity (depth %d)", t.depth)
+	}
+	index := t.count
+	cur := value
+	pos := index
+	for level := 0; level < t.depth; level++ {
+		var (
+			h   *big.Int
+			err error
+		)
+		if pos%2 == 0 {
+			t.filledSubtrees[level] = cur
+			h, err = hash2(cur, t.zeroes[level])
+		} else {
+			h, err = hash2(t.filledSubtrees[level], cur)
+		}
+		if err != nil {
+			return 0, err
+		}
+		cur = h
+		pos /= 2
+	}
+	t.root = cur
+	t.count++
+	return index, nil
+}
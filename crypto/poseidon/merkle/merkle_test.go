@@ -0,0 +1,167 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSparseTreeUpdateAndProveRoundTrip(t *testing.T) {
+	tr := NewSparseTree(8)
+
+	key := big.NewInt(5)
+	value := big.NewInt(42)
+	if err := tr.Update(key, value); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	proof, err := tr.Prove(key)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	ok, err := VerifyProof(tr.Root(), key, value, tr.depth, proof)
+	if err != nil {
+		t.Fatalf("VerifyProof: %v", err)
+	}
+	if !ok {
+		t.Fatalf("VerifyProof rejected a proof produced by the same tree")
+	}
+
+	if ok, _ := VerifyProof(tr.Root(), key, big.NewInt(43), tr.depth, proof); ok {
+		t.Fatalf("VerifyProof accepted a proof against the wrong value")
+	}
+}
+
+// TestSparseTreeExclusionProof checks that a key never written to the tree
+// still produces a valid proof of its (zero) value under the current root.
+func TestSparseTreeExclusionProof(t *testing.T) {
+	tr := NewSparseTree(8)
+	if err := tr.Update(big.NewInt(1), big.NewInt(7)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	key := big.NewInt(2)
+	proof, err := tr.Prove(key)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	ok, err := VerifyProof(tr.Root(), key, big.NewInt(0), tr.depth, proof)
+	if err != nil {
+		t.Fatalf("VerifyProof: %v", err)
+	}
+	if !ok {
+		t.Fatalf("VerifyProof rejected an exclusion proof for an untouched key")
+	}
+}
+
+// TestSparseTreeOverwrite checks that updating an existing key moves the
+// root and invalidates proofs against the old value.
+func TestSparseTreeOverwrite(t *testing.T) {
+	tr := NewSparseTree(8)
+	key := big.NewInt(3)
+
+	if err := tr.Update(key, big.NewInt(1)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	oldRoot := tr.Root()
+	oldProof, err := tr.Prove(key)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+
+	if err := tr.Update(key, big.NewInt(2)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if tr.Root().Cmp(oldRoot) == 0 {
+		t.Fatalf("Root did not change after overwriting a leaf")
+	}
+	if ok, _ := VerifyProof(tr.Root(), key, big.NewInt(1), tr.depth, oldProof); ok {
+		t.Fatalf("stale proof against the old value verified under the new root")
+	}
+
+	v, ok := tr.Get(key)
+	if !ok || v.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("Get returned %v, %v, want 2, true", v, ok)
+	}
+}
+
+func TestSparseTreeKeyOutOfRange(t *testing.T) {
+	tr := NewSparseTree(4)
+	oob := big.NewInt(1 << 4) // depth 4 only covers keys [0, 16)
+
+	if err := tr.Update(oob, big.NewInt(1)); err == nil {
+		t.Fatalf("Update accepted a key out of range for the tree depth")
+	}
+	if _, err := tr.Prove(oob); err == nil {
+		t.Fatalf("Prove accepted a key out of range for the tree depth")
+	}
+}
+
+func TestAppendTreeRoundTrip(t *testing.T) {
+	tr := NewAppendTree(4)
+
+	var indices []int
+	for i := 0; i < 5; i++ {
+		idx, err := tr.Append(big.NewInt(int64(i + 1)))
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		indices = append(indices, idx)
+	}
+	for i, idx := range indices {
+		if idx != i {
+			t.Fatalf("Append returned index %d for the %d-th leaf, want %d", idx, i, i)
+		}
+	}
+}
+
+// TestAppendTreeFull checks that Append refuses to exceed the tree's
+// capacity instead of silently overwriting or wrapping around.
+func TestAppendTreeFull(t *testing.T) {
+	tr := NewAppendTree(2) // capacity 4
+	for i := 0; i < 4; i++ {
+		if _, err := tr.Append(big.NewInt(int64(i))); err != nil {
+			t.Fatalf("Append %d: %v", i, err)
+		}
+	}
+	if _, err := tr.Append(big.NewInt(99)); err == nil {
+		t.Fatalf("Append succeeded past the tree's capacity")
+	}
+}
+
+func TestVerifyProofRejectsEmptyProof(t *testing.T) {
+	if ok, err := VerifyProof(big.NewInt(0), big.NewInt(0), big.NewInt(0), 1, Proof{}); ok || err == nil {
+		t.Fatalf("VerifyProof accepted an empty proof: ok=%v err=%v", ok, err)
+	}
+}
+
+// TestVerifyProofRejectsDepthMismatch checks that a proof whose length
+// doesn't match the claimed depth is rejected outright - this is what stops
+// an internal node from a deeper tree being presented as the root of a
+// shallower one along with a truncated proof.
+func TestVerifyProofRejectsDepthMismatch(t *testing.T) {
+	tr := NewSparseTree(8)
+	key, value := big.NewInt(5), big.NewInt(42)
+	if err := tr.Update(key, value); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	proof, err := tr.Prove(key)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+
+	if ok, err := VerifyProof(tr.Root(), key, value, tr.depth-1, proof); ok || err == nil {
+		t.Fatalf("VerifyProof accepted a proof whose length (%d) doesn't match the claimed depth (%d)", len(proof.Siblings), tr.depth-1)
+	}
+	if ok, err := VerifyProof(tr.Root(), key, value, tr.depth+1, proof); ok || err == nil {
+		t.Fatalf("VerifyProof accepted a proof whose length (%d) doesn't match the claimed depth (%d)", len(proof.Siblings), tr.depth+1)
+	}
+
+	// An internal node several levels up is itself a valid Poseidon hash of
+	// a shallower subtree; without the depth check above, the sibling list
+	// truncated to that level would "verify" against it.
+	shallowRoot := tr.nodeAt(4, new(big.Int).Rsh(key, 4))
+	truncated := Proof{Siblings: proof.Siblings[:4]}
+	if ok, err := VerifyProof(shallowRoot, key, value, tr.depth, truncated); ok || err == nil {
+		t.Fatalf("VerifyProof accepted a depth-4 proof against a full-depth claim")
+	}
+}
@@ -0,0 +1,164 @@
+package poseidon
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/iden3/go-iden3-crypto/ff"
+)
+
+// frConstants caches the round constants, sparse partial-round matrices and
+// MDS matrix converted from the package's ff.Element constant tables
+// (c.c / c.s / c.m / c.p, indexed by width-2) into Fr form. The conversion
+// happens once per width, not once per permute call.
+type frConstants struct {
+	c [][]Fr
+	s [][]Fr
+	m [][][]Fr
+	p [][][]Fr
+}
+
+var (
+	frConstOnce sync.Once
+	frConst     frConstants
+)
+
+func elementsToFr(in []*ff.Element) []Fr {
+	out := make([]Fr, len(in))
+	for i, e := range in {
+		v := big.NewInt(0)
+		e.ToBigIntRegular(v)
+		out[i] = FrFromBigInt(v)
+	}
+	return out
+}
+
+func matrixToFr(in [][]*ff.Element) [][]Fr {
+	out := make([][]Fr, len(in))
+	for i, row := range in {
+		out[i] = elementsToFr(row)
+	}
+	return out
+}
+
+func loadFrConstants() {
+	frConst.c = make([][]Fr, len(c.c))
+	for i, arr := range c.c {
+		frConst.c[i] = elementsToFr(arr)
+	}
+	frConst.s = make([][]Fr, len(c.s))
+	for i, arr := range c.s {
+		frConst.s[i] = elementsToFr(arr)
+	}
+	frConst.m = make([][][]Fr, len(c.m))
+	for i, mat := range c.m {
+		frConst.m[i] = matrixToFr(mat)
+	}
+	frConst.p = make([][][]Fr, len(c.p))
+	for i, mat := range c.p {
+		frConst.p[i] = matrixToFr(mat)
+	}
+}
+
+// maxWidth bounds the sponge width (see HashWithCap, width up to
+// len(NROUNDSP)+1) so the hot loop below can keep its scratch space in a
+// fixed-size array instead of allocating.
+const maxWidth = 17
+
+// arkFast adds the round-constant slice c[it:it+len(state)] onto state.
+func arkFast(state []Fr, c []Fr, it int) {
+	for i := range state {
+		state[i] = addFr(state[i], c[it+i])
+	}
+}
+
+// sboxFast raises every element of state to the fifth power.
+func sboxFast(state []Fr) {
+	for i, x := range state {
+		state[i] = sbox5(x)
+	}
+}
+
+func sbox5(x Fr) Fr {
+	x2 := mulFr(x, x)
+	x4 := mulFr(x2, x2)
+	return mulFr(x4, x)
+}
+
+// mixFast overwrites state in place with m*state, using a stack-sized
+// scratch buffer instead of allocating a new slice per call.
+func mixFast(state []Fr, t int, m [][]Fr) {
+	var buf [maxWidth]Fr
+	newState := buf[:t]
+	for i := 0; i < t; i++ {
+		var acc Fr
+		for j := 0; j < t; j++ {
+			acc = addFr(acc, mulFr(m[j][i], state[j]))
+		}
+		newState[i] = acc
+	}
+	copy(state, newState)
+}
+
+// mixPartialFast applies one partial round's sparse t*2-1 update in place:
+// state[0] becomes the dot product of S's first row with the whole state,
+// and every other state[k] is nudged by state[0] scaled by S's tail. This is
+// the O(t) replacement for a full O(t^2) MDS mix during partial rounds,
+// mirroring upstream's use of the precomputed S matrix.
+func mixPartialFast(state []Fr, t int, s []Fr, round int) {
+	base := (t*2 - 1) * round
+
+	var newState0 Fr
+	for j := 0; j < t; j++ {
+		newState0 = addFr(newState0, mulFr(s[base+j], state[j]))
+	}
+
+	for k := 1; k < t; k++ {
+		state[k] = addFr(state[k], mulFr(state[0], s[base+t+k-1]))
+	}
+	state[0] = newState0
+}
+
+// fastPermute runs the Poseidon permutation over a fixed-size Fr state,
+// mirroring upstream's HashWithStateEx on a fixed-size Fr array with no
+// big.Int.Exp and no per-round heap allocation: full rounds mix with the
+// dense MDS matrix M, and partial rounds use the precomputed sparse matrix S
+// (mixPartialFast) instead of a full t*t mix, exactly as the reference
+// implementation does.
+func fastPermute(state []Fr, t int) {
+	frConstOnce.Do(loadFrConstants)
+
+	nRoundsF := NROUNDSF
+	nRoundsP := NROUNDSP[t-2]
+	C := frConst.c[t-2]
+	S := frConst.s[t-2]
+	M := frConst.m[t-2]
+	P := frConst.p[t-2]
+
+	arkFast(state, C, 0)
+
+	for i := 0; i < nRoundsF/2-1; i++ {
+		sboxFast(state)
+		arkFast(state, C, (i+1)*t)
+		mixFast(state, t, M)
+	}
+	sboxFast(state)
+	arkFast(state, C, (nRoundsF/2)*t)
+	mixFast(state, t, P)
+
+	base := (nRoundsF/2 + 1) * t
+	for i := 0; i < nRoundsP; i++ {
+		state[0] = sbox5(state[0])
+		state[0] = addFr(state[0], C[base+i])
+		mixPartialFast(state, t, S, i)
+	}
+
+	base += nRoundsP
+	for i := 0; i < nRoundsF/2-1; i++ {
+		sboxFast(state)
+		arkFast(state, C, base+i*t)
+		mixFast(state, t, M)
+	}
+	sboxFast(state)
+	mixFast(state, t, M)
+}
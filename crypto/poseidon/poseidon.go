@@ -19,88 +19,25 @@ func zero() *ff.Element {
 	return ff.NewElement()
 }
 
-// exp5 performs x^5 mod p
-// https://eprint.iacr.org/2019/458.pdf page 8
-func exp5(a *ff.Element) {
-	a.Exp(*a, big.NewInt(5)) //nolint:gomnd
-}
-
-// exp5state perform exp5 for whole state
-func exp5state(state []*ff.Element) {
-	for i := 0; i < len(state); i++ {
-		exp5(state[i])
-	}
-}
-
-// ark computes Add-Round Key, from the paper https://eprint.iacr.org/2019/458.pdf
-func ark(state []*ff.Element, c []*ff.Element, it int) {
-	for i := 0; i < len(state); i++ {
-		state[i].Add(state[i], c[it+i])
-	}
-}
-
-// mix returns [[matrix]] * [vector]
-func mix(state []*ff.Element, t int, m [][]*ff.Element) []*ff.Element {
-	mul := zero()
-	newState := make([]*ff.Element, t)
-	for i := 0; i < t; i++ {
-		newState[i] = zero()
-	}
-	for i := 0; i < len(state); i++ {
-		newState[i].SetUint64(0)
-		for j := 0; j < len(state); j++ {
-			mul.Mul(m[j][i], state[j])
-			newState[i].Add(newState[i], mul)
-		}
-	}
-	return newState
-}
-
+// permute runs the Poseidon permutation over state. The actual arithmetic
+// happens in fastPermute (permute_fast.go) on a fixed-size Fr array with no
+// big.Int.Exp and no per-round heap allocation; this function only pays the
+// conversion cost at the boundary so the public Hash/HashFixed/HashWithCap
+// sponge bookkeeping below can keep working in terms of *ff.Element.
 func permute(state []*ff.Element, t int) []*ff.Element {
-
-	nRoundsF := NROUNDSF
-	nRoundsP := NROUNDSP[t-2]
-	C := c.c[t-2]
-	S := c.s[t-2]
-	M := c.m[t-2]
-	P := c.p[t-2]
-
-	ark(state, C, 0)
-
-	for i := 0; i < nRoundsF/2-1; i++ {
-		exp5state(state)
-		ark(state, C, (i+1)*t)
-		state = mix(state, t, M)
+	frState := make([]Fr, t)
+	for i, e := range state {
+		v := big.NewInt(0)
+		e.ToBigIntRegular(v)
+		frState[i] = FrFromBigInt(v)
 	}
-	exp5state(state)
-	ark(state, C, (nRoundsF/2)*t)
-	state = mix(state, t, P)
-
-	for i := 0; i < nRoundsP; i++ {
-		exp5(state[0])
-		state[0].Add(state[0], C[(nRoundsF/2+1)*t+i])
-
-		mul := zero()
-		newState0 := zero()
-		for j := 0; j < len(state); j++ {
-			mul.Mul(S[(t*2-1)*i+j], state[j])
-			newState0.Add(newState0, mul)
-		}
 
-		for k := 1; k < t; k++ {
-			mul = zero()
-			state[k] = state[k].Add(state[k], mul.Mul(state[0], S[(t*2-1)*i+t+k-1]))
-		}
-		state[0] = newState0
-	}
+	fastPermute(frState, t)
 
-	for i := 0; i < nRoundsF/2-1; i++ {
-		exp5state(state)
-		ark(state, C, (nRoundsF/2+1)*t+nRoundsP+i*t)
-		state = mix(state, t, M)
+	for i, x := range frState {
+		state[i] = ff.NewElement().SetBigInt(x.BigInt())
 	}
-	exp5state(state)
-	return mix(state, t, M)
+	return state
 }
 
 // for short, use size of inpBI as cap
@@ -108,13 +45,13 @@ func Hash(inpBI []*big.Int, width int) (*big.Int, error) {
 	return HashWithCap(inpBI, width, int64(len(inpBI)))
 }
 
-// Hash using possible sponge specs specified by width (rate from 1 to 15), the size of input is applied as capacity
+// Hash using possible sponge specs specified by width (2 to len(NROUNDSP)+1, i.e. rate from 1 to 16), the size of input is applied as capacity
 // (notice we do not include width in the capacity )
 func HashWithCap(inpBI []*big.Int, width int, cap int64) (*big.Int, error) {
 	if width < 2 {
 		return nil, fmt.Errorf("width must be ranged from 2 to 16")
 	}
-	if width-2 > len(NROUNDSP) {
+	if width-2 >= len(NROUNDSP) {
 		return nil, fmt.Errorf("invalid inputs width %d, max %d", width, len(NROUNDSP)+1) //nolint:gomnd,lll
 	}
 
@@ -0,0 +1,113 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/ff"
+	"github.com/iden3/go-iden3-crypto/utils"
+)
+
+// Sponge is a stateful Poseidon sponge that absorbs field elements incrementally,
+// permuting only when the rate-sized absorb buffer fills or on Squeeze. It is the
+// incremental counterpart of HashWithCap, useful for callers (e.g. trie walkers)
+// that produce elements one at a time and would otherwise re-allocate per call.
+type Sponge struct {
+	width    int
+	rate     int
+	cap      int64
+	state    []*ff.Element
+	buf      []*ff.Element
+	permuted bool // whether permuteBuf has run at least once since Reset
+}
+
+// NewSponge creates a Sponge for the given width (2 to 16) and capacity/domain flag,
+// matching the width and cap semantics of HashWithCap.
+func NewSponge(width int, cap int64) *Sponge {
+	if width < 2 || width-2 >= len(NROUNDSP) {
+		panic(fmt.Sprintf("poseidon: invalid width %d, must be in [2, %d]", width, len(NROUNDSP)+1))
+	}
+	s := &Sponge{width: width, rate: width - 1, cap: cap}
+	s.Reset()
+	return s
+}
+
+// Reset restores the sponge to its freshly-constructed state, discarding any
+// absorbed-but-unsqueezed input.
+func (s *Sponge) Reset() {
+	s.state = make([]*ff.Element, s.width)
+	s.state[0] = ff.NewElement().SetBigInt(big.NewInt(s.cap))
+	for i := 1; i < s.width; i++ {
+		s.state[i] = zero()
+	}
+	s.buf = make([]*ff.Element, 0, s.rate)
+	s.permuted = false
+}
+
+// Absorb feeds a single field element into the sponge, permuting once the
+// internal buffer reaches the rate.
+func (s *Sponge) Absorb(x *big.Int) error {
+	if !utils.CheckBigIntInField(x) {
+		return errors.New("poseidon: input value not inside Finite Field")
+	}
+	s.buf = append(s.buf, ff.NewElement().SetBigInt(x))
+	if len(s.buf) == s.rate {
+		s.permuteBuf()
+	}
+	return nil
+}
+
+// AbsorbMany is a convenience wrapper that Absorbs each element in order,
+// stopping at the first error.
+func (s *Sponge) AbsorbMany(xs []*big.Int) error {
+	for _, x := range xs {
+		if err := s.Absorb(x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// permuteBuf adds the buffered elements into the rate portion of the state,
+// runs the permutation, and clears the buffer.
+func (s *Sponge) permuteBuf() {
+	for i, elm := range s.buf {
+		s.state[i+1].Add(s.state[i+1], elm)
+	}
+	s.state = permute(s.state, s.width)
+	s.buf = s.buf[:0]
+	s.permuted = true
+}
+
+// Squeeze flushes any buffered-but-unpermuted elements and returns the current
+// squeezed output. The sponge can continue to be used afterwards (further
+// Absorb/Squeeze calls pick up from the resulting state).
+//
+// Squeeze always permutes at least once, even with an empty buffer on a
+// freshly-Reset sponge, matching HashWithCap's final unconditional permute:
+// without this, Squeeze on zero input would return the raw capacity flag
+// instead of a Poseidon digest.
+func (s *Sponge) Squeeze() *big.Int {
+	if len(s.buf) > 0 || !s.permuted {
+		s.permuteBuf()
+	}
+	r := big.NewInt(0)
+	s.state[0].ToBigIntRegular(r)
+	return r
+}
+
+// Clone returns an independent copy of the sponge that can be advanced without
+// affecting the receiver.
+func (s *Sponge) Clone() *Sponge {
+	c := &Sponge{width: s.width, rate: s.rate, cap: s.cap, permuted: s.permuted}
+	c.state = make([]*ff.Element, len(s.state))
+	for i, e := range s.state {
+		c.state[i] = ff.NewElement().Set(e)
+	}
+	c.buf = make([]*ff.Element, len(s.buf), s.rate)
+	for i, e := range s.buf {
+		c.buf[i] = ff.NewElement().Set(e)
+	}
+	return c
+}
@@ -0,0 +1,108 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestSpongeSqueezeZeroAbsorbMatchesHashWithCap pins Squeeze on a freshly
+// constructed (or Reset) sponge with nothing absorbed to HashWithCap(nil, ...)
+// - both must permute the capacity-flag state at least once rather than
+// returning it verbatim.
+func TestSpongeSqueezeZeroAbsorbMatchesHashWithCap(t *testing.T) {
+	const width = 4
+	const cap = 7
+
+	want, err := HashWithCap(nil, width, cap)
+	if err != nil {
+		t.Fatalf("HashWithCap: %v", err)
+	}
+
+	sp := NewSponge(width, cap)
+	if got := sp.Squeeze(); got.Cmp(want) != 0 {
+		t.Fatalf("Squeeze on a zero-absorb sponge = %s, want %s (HashWithCap(nil, ...))", got, want)
+	}
+
+	// Reset then squeeze again: the permuted flag must not leak across Reset.
+	sp.Reset()
+	if got := sp.Squeeze(); got.Cmp(want) != 0 {
+		t.Fatalf("Squeeze after Reset = %s, want %s", got, want)
+	}
+}
+
+// TestSpongeAbsorbMatchesHashWithCap checks that absorbing a full rate's
+// worth of elements and squeezing matches a single HashWithCap call.
+func TestSpongeAbsorbMatchesHashWithCap(t *testing.T) {
+	const width = 3
+	const cap = 2
+	elems := []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	want, err := HashWithCap(elems, width, cap)
+	if err != nil {
+		t.Fatalf("HashWithCap: %v", err)
+	}
+
+	sp := NewSponge(width, cap)
+	if err := sp.AbsorbMany(elems); err != nil {
+		t.Fatalf("AbsorbMany: %v", err)
+	}
+	if got := sp.Squeeze(); got.Cmp(want) != 0 {
+		t.Fatalf("Squeeze = %s, want %s", got, want)
+	}
+}
+
+// TestSpongeSqueezeIsIdempotentWithoutFurtherAbsorb checks that calling
+// Squeeze twice in a row without an intervening Absorb returns the same
+// value both times (no hidden extra permute on the second call).
+func TestSpongeSqueezeIsIdempotentWithoutFurtherAbsorb(t *testing.T) {
+	sp := NewSponge(3, 1)
+	if err := sp.Absorb(big.NewInt(5)); err != nil {
+		t.Fatalf("Absorb: %v", err)
+	}
+	first := sp.Squeeze()
+	second := sp.Squeeze()
+	if first.Cmp(second) != 0 {
+		t.Fatalf("Squeeze is not idempotent: %s vs %s", first, second)
+	}
+}
+
+// TestSpongeCloneIsIndependent checks that advancing a clone does not affect
+// the original, and that the permuted flag carries over from the source.
+func TestSpongeCloneIsIndependent(t *testing.T) {
+	sp := NewSponge(3, 0)
+	if err := sp.Absorb(big.NewInt(1)); err != nil {
+		t.Fatalf("Absorb: %v", err)
+	}
+	want := sp.Squeeze()
+
+	clone := sp.Clone()
+	if err := clone.Absorb(big.NewInt(99)); err != nil {
+		t.Fatalf("Absorb on clone: %v", err)
+	}
+	clone.Squeeze()
+
+	if got := sp.Squeeze(); got.Cmp(want) != 0 {
+		t.Fatalf("advancing the clone changed the original sponge's output: got %s, want %s", got, want)
+	}
+}
+
+// TestSpongeRejectsOutOfFieldInput checks Absorb validates its input the same
+// way HashWithCap/HashFixed do.
+func TestSpongeRejectsOutOfFieldInput(t *testing.T) {
+	sp := NewSponge(3, 0)
+	tooLarge := new(big.Int).Add(frModulusBig, big.NewInt(1))
+	if err := sp.Absorb(tooLarge); err == nil {
+		t.Fatalf("Absorb accepted a value outside the BN254 scalar field")
+	}
+}
+
+// TestNewSpongeRejectsInvalidWidth checks NewSponge panics on a width outside
+// [2, len(NROUNDSP)+1], matching HashWithCap's accepted range.
+func TestNewSpongeRejectsInvalidWidth(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("NewSponge did not panic on an out-of-range width")
+		}
+	}()
+	NewSponge(1, 0)
+}